@@ -16,15 +16,35 @@
 package collector
 
 import (
+	"bufio"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/procfs/btrfs"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
 )
 
+var btrfsScrubStatusPath = kingpin.Flag(
+	"collector.btrfs.scrub-status-path",
+	"Directory holding the scrub status files written by `btrfs scrub`, one per filesystem UUID.",
+).Default("/var/lib/btrfs").String()
+
+var btrfsQgroupsEnabled = kingpin.Flag(
+	"collector.btrfs.qgroups",
+	"Expose qgroup/subvolume metrics (can be expensive on filesystems with many subvolumes).",
+).Default("false").Bool()
+
 // A btrfsCollector is a Collector which gathers metrics from Btrfs filesystems.
 type btrfsCollector struct {
 	fs btrfs.FS
+	// sysPath is the sysfs mount backing fs, kept alongside it so that the
+	// collector can read the parts of /sys/fs/btrfs/<uuid> that
+	// github.com/prometheus/procfs/btrfs doesn't parse itself yet.
+	sysPath string
 }
 
 func init() {
@@ -39,10 +59,18 @@ func NewBtrfsCollector() (Collector, error) {
 	}
 
 	return &btrfsCollector{
-		fs: fs,
+		fs:      fs,
+		sysPath: *sysPath,
 	}, nil
 }
 
+// fsSysfsPath builds a path under the sysfs directory for the filesystem
+// identified by uuid, rooted at the same sysfs mount as the collector's
+// btrfs.FS handle.
+func (c *btrfsCollector) fsSysfsPath(uuid string, parts ...string) string {
+	return filepath.Join(append([]string{c.sysPath, "fs", "btrfs", uuid}, parts...)...)
+}
+
 // Update implements Collector.
 func (c *btrfsCollector) Update(ch chan<- prometheus.Metric) error {
 	stats, err := c.fs.Stats()
@@ -51,23 +79,68 @@ func (c *btrfsCollector) Update(ch chan<- prometheus.Metric) error {
 	}
 
 	for _, s := range stats {
-		c.updateBtrfsStats(ch, s)
+		if err := c.updateBtrfsStats(ch, s); err != nil {
+			return fmt.Errorf("failed to retrieve Btrfs device error or allocation stats: %v", err)
+		}
+		if err := c.updateScrubAndBalanceStats(ch, s); err != nil {
+			return fmt.Errorf("failed to retrieve Btrfs scrub/balance stats: %v", err)
+		}
+		if *btrfsQgroupsEnabled {
+			if err := c.updateQgroupStats(ch, s); err != nil {
+				return fmt.Errorf("failed to retrieve Btrfs qgroup stats: %v", err)
+			}
+		}
 	}
 
 	return nil
 }
 
 type btrfsMetric struct {
-	name  string
-	desc  string
-	value float64
-	//metricType      prometheus.ValueType
+	name            string
+	desc            string
+	value           float64
+	metricType      prometheus.ValueType
 	extraLabel      []string
 	extraLabelValue []string
 }
 
+// emitMetrics sends metrics on ch, one Prometheus metric per entry. devLabels
+// and devLabelValues are the label names/values common to every metric (e.g.
+// filesystem label and uuid); each metric's own extraLabel/extraLabelValue
+// are appended on top. A metric without an explicit metricType is reported
+// as a gauge.
+func (c *btrfsCollector) emitMetrics(ch chan<- prometheus.Metric, subsystem string, devLabels, devLabelValues []string, metrics []btrfsMetric) {
+	for _, m := range metrics {
+		labels := append(devLabels, m.extraLabel...)
+
+		desc := prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, m.name),
+			m.desc,
+			labels,
+			nil,
+		)
+
+		labelValues := devLabelValues
+		if len(m.extraLabelValue) > 0 {
+			labelValues = append(devLabelValues, m.extraLabelValue...)
+		}
+
+		metricType := m.metricType
+		if metricType == 0 {
+			metricType = prometheus.GaugeValue
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			desc,
+			metricType,
+			m.value,
+			labelValues...,
+		)
+	}
+}
+
 // UpdateBtrfsStats collects statistics for one bcache ID.
-func (c *btrfsCollector) updateBtrfsStats(ch chan<- prometheus.Metric, s *btrfs.Stats) {
+func (c *btrfsCollector) updateBtrfsStats(ch chan<- prometheus.Metric, s *btrfs.Stats) error {
 	const (
 		subsystem = "btrfs"
 	)
@@ -89,44 +162,147 @@ func (c *btrfsCollector) updateBtrfsStats(ch chan<- prometheus.Metric, s *btrfs.
 	for n, dev := range s.Devices {
 		metrics = append(metrics, []btrfsMetric{
 			{
-				name:  "device_size",
-				desc:  "Size of a device that is part of the filesystem.",
-				value: float64(dev.Size),
+				name:            "device_size",
+				desc:            "Size of a device that is part of the filesystem.",
+				value:           float64(dev.Size),
 				extraLabel:      []string{"device"},
 				extraLabelValue: []string{n},
 			},
 		}...)
 	}
 
-	metrics = append(metrics, c.getAllocationStats("data", s.Allocation.Data)...)
-	metrics = append(metrics, c.getAllocationStats("metadata", s.Allocation.Metadata)...)
-	metrics = append(metrics, c.getAllocationStats("system", s.Allocation.System)...)
+	errStats, err := c.getDeviceErrorStats(s.UUID)
+	if err != nil {
+		return err
+	}
+	metrics = append(metrics, errStats...)
 
-	for _, m := range metrics {
-		labels := append(devLabels, m.extraLabel...)
+	for _, a := range []struct {
+		name  string
+		stats *btrfs.AllocationStats
+	}{
+		{"data", s.Allocation.Data},
+		{"metadata", s.Allocation.Metadata},
+		{"system", s.Allocation.System},
+	} {
+		allocStats, err := c.getAllocationStats(s.UUID, a.name, a.stats)
+		if err != nil {
+			return err
+		}
+		metrics = append(metrics, allocStats...)
+	}
 
-		desc := prometheus.NewDesc(
-			prometheus.BuildFQName(namespace, subsystem, m.name),
-			m.desc,
-			labels,
-			nil,
-		)
+	c.emitMetrics(ch, subsystem, devLabels, []string{s.Label, s.UUID}, metrics)
 
-		labelValues := []string{s.Label, s.UUID}
-		if len(m.extraLabelValue) > 0 {
-			labelValues = append(labelValues, m.extraLabelValue...)
+	return nil
+}
+
+// getDeviceErrorStats reads the per-device I/O error counters Btrfs exposes
+// under the sysfs devinfo tree for the filesystem identified by uuid (the
+// same values shown by `btrfs device stats`), keyed by devid since that's
+// the only identifier the sysfs tree carries for a device. Unlike the
+// devices/ subtree, devinfo/<devid> are plain directories rather than
+// symlinks, so they can be filtered on directly. It returns no metrics,
+// without error, for devices that don't publish error_stats.
+func (c *btrfsCollector) getDeviceErrorStats(uuid string) ([]btrfsMetric, error) {
+	devinfoPath := c.fsSysfsPath(uuid, "devinfo")
+
+	entries, err := os.ReadDir(devinfoPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var metrics []btrfsMetric
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
 		}
 
-		ch <- prometheus.MustNewConstMetric(
-			desc,
-			prometheus.GaugeValue,
-			m.value,
-			labelValues...,
-		)
+		fields, err := readKeyValueFile(filepath.Join(devinfoPath, e.Name(), "error_stats"), "")
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		metrics = append(metrics, c.newDeviceErrorMetrics(e.Name(), fields)...)
+	}
+
+	return metrics, nil
+}
+
+// newDeviceErrorMetrics turns the parsed error_stats fields for one device
+// (named after its devid, as sysfs does) into counters.
+func (c *btrfsCollector) newDeviceErrorMetrics(devid string, fields map[string]uint64) []btrfsMetric {
+	errLabel := []string{"device", "type"}
+	newErrMetric := func(errType, key string) btrfsMetric {
+		return btrfsMetric{
+			name:            "device_errors_total",
+			desc:            "Number of errors encountered by a device of the filesystem.",
+			value:           float64(fields[key]),
+			metricType:      prometheus.CounterValue,
+			extraLabel:      errLabel,
+			extraLabelValue: []string{devid, errType},
+		}
+	}
+
+	return []btrfsMetric{
+		newErrMetric("write", "write_io_errs"),
+		newErrMetric("read", "read_io_errs"),
+		newErrMetric("flush", "flush_io_errs"),
+		newErrMetric("corruption", "corruption_errs"),
+		newErrMetric("generation", "generation_errs"),
+	}
+}
+
+// readKeyValueFile parses a text file made of "key<sep>value" lines into a
+// map, skipping lines that don't parse as a key plus a uint64 value. An empty
+// sep splits each line on whitespace instead of a literal separator.
+func readKeyValueFile(path, sep string) (map[string]uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fields := map[string]uint64{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var key, val string
+		if sep == "" {
+			parts := strings.Fields(line)
+			if len(parts) != 2 {
+				continue
+			}
+			key, val = parts[0], parts[1]
+		} else {
+			kv := strings.SplitN(line, sep, 2)
+			if len(kv) != 2 {
+				continue
+			}
+			key, val = kv[0], kv[1]
+		}
+
+		v, err := strconv.ParseUint(val, 10, 64)
+		if err != nil {
+			continue
+		}
+		fields[key] = v
 	}
+
+	return fields, scanner.Err()
 }
 
-func (c *btrfsCollector) getAllocationStats(a string, s *btrfs.AllocationStats) []btrfsMetric {
+func (c *btrfsCollector) getAllocationStats(uuid, a string, s *btrfs.AllocationStats) ([]btrfsMetric, error) {
 	metrics := []btrfsMetric{
 		{
 			name:            "reserved_bytes",
@@ -145,7 +321,235 @@ func (c *btrfsCollector) getAllocationStats(a string, s *btrfs.AllocationStats)
 	metrics = append(metrics, c.getLayoutStats(a, "raid6", s.Raid6)...)
 	metrics = append(metrics, c.getLayoutStats(a, "raid10", s.Raid10)...)
 
-	return metrics
+	// raid1c3/raid1c4 aren't parsed by the vendored procfs/btrfs package yet,
+	// so read their usage straight from the sysfs allocation tree instead.
+	raid1c3, err := c.getSysfsLayoutStats(uuid, a, "raid1c3", 3)
+	if err != nil {
+		return nil, err
+	}
+	metrics = append(metrics, raid1c3...)
+
+	raid1c4, err := c.getSysfsLayoutStats(uuid, a, "raid1c4", 4)
+	if err != nil {
+		return nil, err
+	}
+	metrics = append(metrics, raid1c4...)
+
+	return metrics, nil
+}
+
+// updateScrubAndBalanceStats collects metrics about the state of the
+// long-running scrub and balance maintenance operations for one filesystem.
+func (c *btrfsCollector) updateScrubAndBalanceStats(ch chan<- prometheus.Metric, s *btrfs.Stats) error {
+	const (
+		subsystem = "btrfs"
+	)
+
+	devLabels := []string{"label", "uuid"}
+
+	metrics, err := c.getScrubStats(s.UUID)
+	if err != nil {
+		return err
+	}
+
+	balance, err := c.getBalanceStats(s.UUID)
+	if err != nil {
+		return err
+	}
+	metrics = append(metrics, balance...)
+
+	c.emitMetrics(ch, subsystem, devLabels, []string{s.Label, s.UUID}, metrics)
+
+	return nil
+}
+
+// Field order of a device record in the scrub status file, after the leading
+// filesystem uuid field.
+const (
+	scrubFieldStarted = iota
+	scrubFieldFinished
+	scrubFieldDataBytesScrubbed
+	scrubFieldReadErrors
+	scrubFieldCsumErrors
+	scrubFieldVerifyErrors
+	scrubFieldSuperErrors
+	scrubFieldMallocErrors
+	scrubFieldUncorrectableErrors
+	scrubFieldUnverifiedErrors
+	scrubFieldCorrectedErrors
+	scrubNumFields
+)
+
+// getScrubStats reads the scrub status file written by `btrfs scrub` for the
+// filesystem identified by uuid and turns it into metrics. It returns no
+// metrics, without error, if the filesystem has never been scrubbed.
+//
+// The file holds a version header line followed by one pipe-delimited record
+// per device that took part in the scrub:
+//
+//	uuid|started|finished|data_bytes_scrubbed|read_errors|csum_errors|
+//	verify_errors|super_errors|malloc_errors|uncorrectable_errors|
+//	unverified_errors|corrected_errors
+//
+// finished is 0 while that device is still being scrubbed. Counters are
+// summed across devices; the scrub is considered in progress as long as any
+// device hasn't finished.
+func (c *btrfsCollector) getScrubStats(uuid string) ([]btrfsMetric, error) {
+	path := filepath.Join(*btrfsScrubStatusPath, "scrub.status."+uuid)
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		lastFinished                                        uint64
+		bytesScrubbed, readErrors, csumErrors, verifyErrors uint64
+		superErrors, uncorrectableErrors                    uint64
+		sawDevice, inProgress                               bool
+	)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "scrub status:") {
+			continue
+		}
+
+		parts := strings.Split(line, "|")
+		if len(parts) < scrubNumFields+1 || parts[0] != uuid {
+			continue
+		}
+		fields := parts[1:]
+
+		parse := func(i int) uint64 {
+			v, _ := strconv.ParseUint(fields[i], 10, 64)
+			return v
+		}
+
+		sawDevice = true
+		if finished := parse(scrubFieldFinished); finished == 0 {
+			inProgress = true
+		} else if finished > lastFinished {
+			lastFinished = finished
+		}
+
+		bytesScrubbed += parse(scrubFieldDataBytesScrubbed)
+		readErrors += parse(scrubFieldReadErrors)
+		csumErrors += parse(scrubFieldCsumErrors)
+		verifyErrors += parse(scrubFieldVerifyErrors)
+		superErrors += parse(scrubFieldSuperErrors)
+		uncorrectableErrors += parse(scrubFieldUncorrectableErrors)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !sawDevice {
+		return nil, nil
+	}
+
+	errLabel := []string{"type"}
+	newErrMetric := func(errType string, value uint64) btrfsMetric {
+		return btrfsMetric{
+			name:            "scrub_errors_total",
+			desc:            "Number of errors found by a scrub, by type.",
+			value:           float64(value),
+			metricType:      prometheus.CounterValue,
+			extraLabel:      errLabel,
+			extraLabelValue: []string{errType},
+		}
+	}
+
+	var inProgressValue float64
+	if inProgress {
+		inProgressValue = 1
+	}
+
+	return []btrfsMetric{
+		{
+			name:       "scrub_last_finished_timestamp_seconds",
+			desc:       "Timestamp at which the last scrub finished.",
+			value:      float64(lastFinished),
+			metricType: prometheus.GaugeValue,
+		},
+		{
+			name:       "scrub_bytes_scrubbed_total",
+			desc:       "Total number of bytes scrubbed.",
+			value:      float64(bytesScrubbed),
+			metricType: prometheus.CounterValue,
+		},
+		{
+			name:       "scrub_in_progress",
+			desc:       "Whether a scrub is currently running on the filesystem.",
+			value:      inProgressValue,
+			metricType: prometheus.GaugeValue,
+		},
+		newErrMetric("csum", csumErrors),
+		newErrMetric("read", readErrors),
+		newErrMetric("verify", verifyErrors),
+		newErrMetric("super", superErrors),
+		newErrMetric("uncorrectable", uncorrectableErrors),
+	}, nil
+}
+
+// getBalanceStats reads the balance state exposed under the sysfs balance
+// directory for the filesystem identified by uuid. It returns no metrics,
+// without error, if no balance has ever run on the filesystem.
+func (c *btrfsCollector) getBalanceStats(uuid string) ([]btrfsMetric, error) {
+	balancePath := c.fsSysfsPath(uuid, "balance")
+
+	inProgress, err := readSysfsUint(filepath.Join(balancePath, "in_progress"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	left, err := readSysfsUint(filepath.Join(balancePath, "total_bytes_left"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	total, err := readSysfsUint(filepath.Join(balancePath, "total_bytes"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return []btrfsMetric{
+		{
+			name:       "balance_in_progress",
+			desc:       "Whether a balance is currently running on the filesystem.",
+			value:      float64(inProgress),
+			metricType: prometheus.GaugeValue,
+		},
+		{
+			name:       "balance_bytes_left",
+			desc:       "Number of bytes left to balance.",
+			value:      float64(left),
+			metricType: prometheus.GaugeValue,
+		},
+		{
+			name:       "balance_bytes_total",
+			desc:       "Total number of bytes considered for the running balance.",
+			value:      float64(total),
+			metricType: prometheus.GaugeValue,
+		},
+	}, nil
+}
+
+// readSysfsUint reads a sysfs file holding a single unsigned integer value.
+func readSysfsUint(path string) (uint64, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
 }
 
 func (c *btrfsCollector) getLayoutStats(a, l string, s *btrfs.LayoutUsage) []btrfsMetric {
@@ -153,27 +557,170 @@ func (c *btrfsCollector) getLayoutStats(a, l string, s *btrfs.LayoutUsage) []btr
 		return nil
 	}
 
+	return c.newLayoutMetrics(a, l, float64(s.UsedBytes), float64(s.TotalBytes), s.Ratio)
+}
+
+// getSysfsLayoutStats reads the usage of an allocation profile directly from
+// the sysfs allocation tree, for profiles the vendored procfs/btrfs package
+// doesn't parse yet. ratio is the fixed per-profile allocation multiplier
+// (e.g. 3 for raid1c3, 4 for raid1c4). It returns no metrics, without error,
+// if the filesystem doesn't use the profile, but propagates any other read
+// failure instead of silently reporting nothing.
+func (c *btrfsCollector) getSysfsLayoutStats(uuid, a, l string, ratio float64) ([]btrfsMetric, error) {
+	path := c.fsSysfsPath(uuid, "allocation", a, l)
+
+	used, err := readSysfsUint(filepath.Join(path, "used_bytes"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := readSysfsUint(filepath.Join(path, "total_bytes"))
+	if err != nil {
+		return nil, err
+	}
+
+	return c.newLayoutMetrics(a, l, float64(used), float64(total), ratio), nil
+}
+
+// newLayoutMetrics builds the used/total/ratio/free metrics shared by every
+// allocation profile, however their usage was fetched.
+func (c *btrfsCollector) newLayoutMetrics(a, l string, used, total, ratio float64) []btrfsMetric {
 	return []btrfsMetric{
 		{
 			name:            "used_bytes",
 			desc:            "Amount of used space by a layout/data type",
-			value:           float64(s.UsedBytes),
+			value:           used,
 			extraLabel:      []string{"type", "mode"},
 			extraLabelValue: []string{a, l},
 		},
 		{
 			name:            "total_bytes",
 			desc:            "Amount of space allocated for a layout/data type",
-			value:           float64(s.TotalBytes),
+			value:           total,
 			extraLabel:      []string{"type", "mode"},
 			extraLabelValue: []string{a, l},
 		},
 		{
 			name:            "ratio",
 			desc:            "Data allocation ratio for a layout/data type",
-			value:           s.Ratio,
+			value:           ratio,
 			extraLabel:      []string{"type", "mode"},
 			extraLabelValue: []string{a, l},
 		},
+		{
+			name:            "free_bytes",
+			desc:            "Amount of free space for a layout/data type",
+			value:           (total - used) / ratio,
+			extraLabel:      []string{"type", "mode"},
+			extraLabelValue: []string{a, l},
+		},
+	}
+}
+
+// updateQgroupStats collects the opt-in qgroup/subvolume usage metrics for
+// one filesystem. It is a no-op when quota tracking isn't enabled on the
+// filesystem.
+func (c *btrfsCollector) updateQgroupStats(ch chan<- prometheus.Metric, s *btrfs.Stats) error {
+	const (
+		subsystem = "btrfs"
+	)
+
+	metrics, err := c.getQgroupStats(s.UUID)
+	if err != nil {
+		return err
+	}
+
+	c.emitMetrics(ch, subsystem, []string{"label", "uuid"}, []string{s.Label, s.UUID}, metrics)
+
+	return nil
+}
+
+// getQgroupStats walks the sysfs qgroups tree of the filesystem identified by
+// uuid and returns the referenced/exclusive usage metrics for every qgroup
+// found there. It returns no metrics, without error, if quota tracking isn't
+// enabled on the filesystem.
+func (c *btrfsCollector) getQgroupStats(uuid string) ([]btrfsMetric, error) {
+	qgroupsPath := c.fsSysfsPath(uuid, "qgroups")
+
+	entries, err := os.ReadDir(qgroupsPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var metrics []btrfsMetric
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+
+		// Qgroup directories are named "<level>_<id>".
+		level, id, ok := strings.Cut(e.Name(), "_")
+		if !ok {
+			continue
+		}
+
+		usage, err := c.getQgroupUsage(qgroupsPath, e.Name(), id, level)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, usage...)
 	}
+
+	return metrics, nil
+}
+
+// getQgroupUsage reads the usage attributes of one qgroup. A missing
+// attribute (e.g. no limit set) is not an error, but any other read failure
+// is, so a bad attribute name or permission problem surfaces instead of
+// silently reporting nothing.
+func (c *btrfsCollector) getQgroupUsage(qgroupsPath, dir, qgroupid, level string) ([]btrfsMetric, error) {
+	extraLabel := []string{"qgroupid", "level"}
+	extraLabelValue := []string{qgroupid, level}
+
+	newUsageMetric := func(name, desc, file string) (btrfsMetric, error) {
+		v, err := readSysfsUint(filepath.Join(qgroupsPath, dir, file))
+		if os.IsNotExist(err) {
+			return btrfsMetric{}, nil
+		}
+		if err != nil {
+			return btrfsMetric{}, err
+		}
+
+		return btrfsMetric{
+			name:            name,
+			desc:            desc,
+			value:           float64(v),
+			extraLabel:      extraLabel,
+			extraLabelValue: extraLabelValue,
+		}, nil
+	}
+
+	// The kernel exposes these as rfer/excl/max_rfer/max_excl, the same
+	// abbreviations btrfs-progs uses for the qgroup usage/limit columns.
+	defs := []struct{ name, desc, file string }{
+		{"qgroup_referenced_bytes", "Amount of data referenced by a qgroup.", "rfer"},
+		{"qgroup_exclusive_bytes", "Amount of data exclusively owned by a qgroup.", "excl"},
+		{"qgroup_max_referenced_bytes", "Limit on the referenced bytes of a qgroup.", "max_rfer"},
+		{"qgroup_max_exclusive_bytes", "Limit on the exclusive bytes of a qgroup.", "max_excl"},
+	}
+
+	var metrics []btrfsMetric
+	for _, d := range defs {
+		m, err := newUsageMetric(d.name, d.desc, d.file)
+		if err != nil {
+			return nil, err
+		}
+		if m.name == "" {
+			continue
+		}
+		metrics = append(metrics, m)
+	}
+
+	return metrics, nil
 }
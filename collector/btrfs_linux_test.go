@@ -0,0 +1,228 @@
+// Copyright 2019 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !nobtrfs
+
+package collector
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBtrfsGetScrubStats(t *testing.T) {
+	orig := *btrfsScrubStatusPath
+	*btrfsScrubStatusPath = "testdata/btrfs"
+	defer func() { *btrfsScrubStatusPath = orig }()
+
+	c := &btrfsCollector{}
+	metrics, err := c.getScrubStats("test-uuid")
+	if err != nil {
+		t.Fatalf("getScrubStats: %v", err)
+	}
+
+	got := map[string]float64{}
+	for _, m := range metrics {
+		key := m.name
+		if len(m.extraLabelValue) > 0 {
+			key += "_" + m.extraLabelValue[len(m.extraLabelValue)-1]
+		}
+		got[key] = m.value
+	}
+
+	want := map[string]float64{
+		"scrub_last_finished_timestamp_seconds": 1690000500,
+		"scrub_bytes_scrubbed_total":            123456,
+		"scrub_in_progress":                     0,
+		"scrub_errors_total_csum":               1,
+		"scrub_errors_total_read":               2,
+		"scrub_errors_total_verify":             3,
+		"scrub_errors_total_super":              4,
+		"scrub_errors_total_uncorrectable":      5,
+	}
+
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("metric %s = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestBtrfsGetDeviceErrorStats(t *testing.T) {
+	c := &btrfsCollector{sysPath: "testdata/btrfs/sys"}
+	metrics, err := c.getDeviceErrorStats("test-uuid")
+	if err != nil {
+		t.Fatalf("getDeviceErrorStats: %v", err)
+	}
+
+	got := map[string]float64{}
+	for _, m := range metrics {
+		key := m.name + "_" + strings.Join(m.extraLabelValue, "_")
+		got[key] = m.value
+	}
+
+	want := map[string]float64{
+		"device_errors_total_1_write":      1,
+		"device_errors_total_1_read":       2,
+		"device_errors_total_1_flush":      3,
+		"device_errors_total_1_corruption": 4,
+		"device_errors_total_1_generation": 5,
+		"device_errors_total_2_write":      0,
+		"device_errors_total_2_read":       0,
+		"device_errors_total_2_flush":      0,
+		"device_errors_total_2_corruption": 0,
+		"device_errors_total_2_generation": 0,
+	}
+
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("metric %s = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestBtrfsGetDeviceErrorStatsNoDevinfo(t *testing.T) {
+	c := &btrfsCollector{sysPath: "testdata/btrfs/sys"}
+	metrics, err := c.getDeviceErrorStats("does-not-exist")
+	if err != nil {
+		t.Fatalf("getDeviceErrorStats: %v", err)
+	}
+	if metrics != nil {
+		t.Errorf("expected no metrics for a filesystem without a devinfo tree, got %v", metrics)
+	}
+}
+
+func TestBtrfsGetBalanceStats(t *testing.T) {
+	c := &btrfsCollector{sysPath: "testdata/btrfs/sys"}
+	metrics, err := c.getBalanceStats("test-uuid")
+	if err != nil {
+		t.Fatalf("getBalanceStats: %v", err)
+	}
+
+	got := map[string]float64{}
+	for _, m := range metrics {
+		got[m.name] = m.value
+	}
+
+	want := map[string]float64{
+		"balance_in_progress": 1,
+		"balance_bytes_left":  12345,
+		"balance_bytes_total": 98765,
+	}
+
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("metric %s = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestBtrfsGetBalanceStatsNeverBalanced(t *testing.T) {
+	c := &btrfsCollector{sysPath: "testdata/btrfs/sys"}
+	metrics, err := c.getBalanceStats("does-not-exist")
+	if err != nil {
+		t.Fatalf("getBalanceStats: %v", err)
+	}
+	if metrics != nil {
+		t.Errorf("expected no metrics for a filesystem that was never balanced, got %v", metrics)
+	}
+}
+
+func TestBtrfsGetSysfsLayoutStats(t *testing.T) {
+	c := &btrfsCollector{sysPath: "testdata/btrfs/sys"}
+	metrics, err := c.getSysfsLayoutStats("test-uuid", "data", "raid1c3", 3)
+	if err != nil {
+		t.Fatalf("getSysfsLayoutStats: %v", err)
+	}
+
+	got := map[string]float64{}
+	for _, m := range metrics {
+		got[m.name] = m.value
+	}
+
+	want := map[string]float64{
+		"used_bytes":  300,
+		"total_bytes": 900,
+		"ratio":       3,
+		"free_bytes":  (900 - 300) / 3,
+	}
+
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("metric %s = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestBtrfsGetSysfsLayoutStatsNoProfile(t *testing.T) {
+	c := &btrfsCollector{sysPath: "testdata/btrfs/sys"}
+	metrics, err := c.getSysfsLayoutStats("test-uuid", "data", "raid1c4", 4)
+	if err != nil {
+		t.Fatalf("getSysfsLayoutStats: %v", err)
+	}
+	if metrics != nil {
+		t.Errorf("expected no metrics for an unused allocation profile, got %v", metrics)
+	}
+}
+
+func TestBtrfsGetQgroupStats(t *testing.T) {
+	c := &btrfsCollector{sysPath: "testdata/btrfs/sys"}
+	metrics, err := c.getQgroupStats("test-uuid")
+	if err != nil {
+		t.Fatalf("getQgroupStats: %v", err)
+	}
+
+	got := map[string]float64{}
+	for _, m := range metrics {
+		got[m.name] = m.value
+	}
+
+	want := map[string]float64{
+		"qgroup_referenced_bytes":     1000,
+		"qgroup_exclusive_bytes":      500,
+		"qgroup_max_referenced_bytes": 2000,
+		"qgroup_max_exclusive_bytes":  1500,
+	}
+
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("metric %s = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestBtrfsGetQgroupStatsNoQuota(t *testing.T) {
+	c := &btrfsCollector{sysPath: "testdata/btrfs/sys"}
+	metrics, err := c.getQgroupStats("does-not-exist")
+	if err != nil {
+		t.Fatalf("getQgroupStats: %v", err)
+	}
+	if metrics != nil {
+		t.Errorf("expected no metrics for a filesystem without quota enabled, got %v", metrics)
+	}
+}
+
+func TestBtrfsGetScrubStatsNeverScrubbed(t *testing.T) {
+	orig := *btrfsScrubStatusPath
+	*btrfsScrubStatusPath = "testdata/btrfs"
+	defer func() { *btrfsScrubStatusPath = orig }()
+
+	c := &btrfsCollector{}
+	metrics, err := c.getScrubStats("does-not-exist")
+	if err != nil {
+		t.Fatalf("getScrubStats: %v", err)
+	}
+	if metrics != nil {
+		t.Errorf("expected no metrics for a filesystem that was never scrubbed, got %v", metrics)
+	}
+}